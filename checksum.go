@@ -0,0 +1,18 @@
+package srec
+
+// ComputeChecksum computes the standard S-record checksum: the one's
+// complement of the low byte of length + address bytes + data bytes.
+// The address field width is derived from length, since by the S-record
+// spec length counts exactly the address, data and checksum bytes.
+func ComputeChecksum(length uint32, addr uint32, data []byte) byte {
+	addrLen := int(length) - len(data) - 1
+
+	sum := length
+	for i := addrLen - 1; i >= 0; i-- {
+		sum += (addr >> uint(8*i)) & 0xFF
+	}
+	for _, b := range data {
+		sum += uint32(b)
+	}
+	return byte(0xFF - byte(sum))
+}
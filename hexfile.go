@@ -0,0 +1,16 @@
+package srec
+
+import "io"
+
+// HexFile is the common surface implemented by the firmware record
+// formats this module understands (Motorola S-record, Intel HEX). It
+// lets callers convert between formats without caring which one a given
+// file happens to be.
+type HexFile interface {
+	Parse(r io.Reader) error
+	Bytes() []byte
+	SetBytes(addr uint32, data []byte) error
+	io.WriterTo
+}
+
+var _ HexFile = (*Srec)(nil)
@@ -0,0 +1,213 @@
+package srec
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	ihexDataRecordType             byte = 0x00
+	ihexEndOfFileRecordType        byte = 0x01
+	ihexExtSegmentAddrRecordType   byte = 0x02
+	ihexStartSegmentAddrRecordType byte = 0x03
+	ihexExtLinearAddrRecordType    byte = 0x04
+	ihexStartLinearAddrRecordType  byte = 0x05
+
+	ihexRecordDataLen = 16
+)
+
+var _ HexFile = (*Ihex)(nil)
+
+// ihexRecord is one "00" data record, resolved to an absolute address
+// via the preceding 02/04 extended address record, if any.
+type ihexRecord struct {
+	address uint32
+	data    []byte
+}
+
+// Ihex holds the data records parsed from an Intel HEX file, exposed as
+// the same MemoryImage that Srec exposes for S-record data.
+type Ihex struct {
+	dataRecords []*ihexRecord
+	MemoryImage
+}
+
+func NewIhex() *Ihex {
+	return &Ihex{}
+}
+
+// Parse reads an Intel HEX file from r with 0xFF fill and gaps wider
+// than defaultGapThreshold split into separate segments.
+func (ih *Ihex) Parse(r io.Reader) error {
+	return ih.ParseWithOptions(r, ParseOptions{FillByte: 0xFF, GapPolicy: GapSplit})
+}
+
+// ParseWithOptions parses an Intel HEX file the way Parse does, but with
+// the fill byte taken from opts.FillByte and the gap-splitting threshold
+// from opts.GapThreshold. opts.GapPolicy and opts.RequireAscendingAddr
+// are not consulted.
+func (ih *Ihex) ParseWithOptions(r io.Reader, opts ParseOptions) error {
+	scanner := bufio.NewScanner(r)
+
+	var extAddr uint32
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, ":") {
+			return fmt.Errorf("ihex line %q does not start with ':'.", line)
+		}
+
+		raw, err := hex.DecodeString(line[1:])
+		if err != nil {
+			return fmt.Errorf("ihex line %q is not valid hex: %w", line, err)
+		}
+		if len(raw) < 5 {
+			return fmt.Errorf("ihex line %q is too short.", line)
+		}
+
+		length := int(raw[0])
+		fieldAddr := uint32(raw[1])<<8 | uint32(raw[2])
+		rectype := raw[3]
+		if len(raw) != 4+length+1 {
+			return fmt.Errorf("ihex line %q has a length field that does not match its data.", line)
+		}
+		data := raw[4 : 4+length]
+		checksum := raw[4+length]
+
+		var sum byte
+		for _, b := range raw[:len(raw)-1] {
+			sum += b
+		}
+		if -sum != checksum {
+			return fmt.Errorf("ihex line %q has a bad checksum: expected 0x%02X, got 0x%02X.", line, -sum, checksum)
+		}
+
+		switch rectype {
+		case ihexDataRecordType:
+			ih.dataRecords = append(ih.dataRecords, &ihexRecord{
+				address: extAddr + fieldAddr,
+				data:    append([]byte{}, data...),
+			})
+		case ihexEndOfFileRecordType:
+		case ihexExtSegmentAddrRecordType:
+			if len(data) != 2 {
+				return fmt.Errorf("ihex line %q has a malformed extended segment address record.", line)
+			}
+			segBase := uint32(data[0])<<8 | uint32(data[1])
+			extAddr = segBase * 16
+		case ihexExtLinearAddrRecordType:
+			if len(data) != 2 {
+				return fmt.Errorf("ihex line %q has a malformed extended linear address record.", line)
+			}
+			extAddr = (uint32(data[0])<<8 | uint32(data[1])) << 16
+		case ihexStartSegmentAddrRecordType, ihexStartLinearAddrRecordType:
+			// entry point records, irrelevant to the memory image.
+		default:
+			return fmt.Errorf("ihex line %q has an unsupported record type 0x%02X.", line, rectype)
+		}
+	}
+
+	if len(ih.dataRecords) == 0 {
+		return fmt.Errorf("byte data is empty. call Parse() or maybe ihex file has no data records.")
+	}
+
+	start, end := ihexAddrRange(ih.dataRecords)
+	ih.startAddress = start
+	ih.endAddress = end - 1
+
+	runs := make([]addrRun, len(ih.dataRecords))
+	for i, rec := range ih.dataRecords {
+		runs[i] = addrRun{addr: rec.address, data: rec.data}
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].addr < runs[j].addr })
+	ih.fillByte = opts.FillByte
+	segments, err := buildSegments(runs, ih.fillByte, opts.gapThreshold(), GapSplit)
+	if err != nil {
+		return err
+	}
+	ih.segments = segments
+	if len(ih.segments) == 1 {
+		ih.dataBytes = ih.segments[0].Data
+	}
+	return nil
+}
+
+func ihexAddrRange(records []*ihexRecord) (start uint32, end uint32) {
+	start = records[0].address
+	end = records[0].address + uint32(len(records[0].data))
+	for _, rec := range records[1:] {
+		if rec.address < start {
+			start = rec.address
+		}
+		if recEnd := rec.address + uint32(len(rec.data)); recEnd > end {
+			end = recEnd
+		}
+	}
+	return start, end
+}
+
+// WriteTo serializes the current memory image back out as Intel HEX,
+// using 04 extended linear address records whenever a 64KB boundary is
+// crossed. It walks Segments() rather than Bytes(), so sparse images
+// serialize too.
+func (ih *Ihex) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+	var curUpper uint32 = 0xFFFFFFFF
+
+	for _, seg := range ih.segments {
+		data := seg.Data
+		for off := 0; off < len(data); off += ihexRecordDataLen {
+			end := off + ihexRecordDataLen
+			if end > len(data) {
+				end = len(data)
+			}
+
+			addr := seg.Addr + uint32(off)
+			upper := addr >> 16
+			if upper != curUpper {
+				n, err := writeIhexRecord(w, 0, ihexExtLinearAddrRecordType, []byte{byte(upper >> 8), byte(upper)})
+				written += n
+				if err != nil {
+					return written, err
+				}
+				curUpper = upper
+			}
+
+			n, err := writeIhexRecord(w, uint16(addr), ihexDataRecordType, data[off:end])
+			written += n
+			if err != nil {
+				return written, err
+			}
+		}
+	}
+
+	n, err := writeIhexRecord(w, 0, ihexEndOfFileRecordType, nil)
+	written += n
+	return written, err
+}
+
+func writeIhexRecord(w io.Writer, addr uint16, rectype byte, data []byte) (int64, error) {
+	length := byte(len(data))
+
+	sum := length + byte(addr>>8) + byte(addr) + rectype
+	for _, b := range data {
+		sum += b
+	}
+	checksum := -sum
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, ":%02X%04X%02X", length, addr, rectype)
+	for _, b := range data {
+		fmt.Fprintf(&sb, "%02X", b)
+	}
+	fmt.Fprintf(&sb, "%02X\n", checksum)
+
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
@@ -0,0 +1,61 @@
+package srec
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestIhexWriteToMultiSegment guards against WriteTo silently dropping
+// every byte of a sparse image, which used to happen because it read the
+// flat dataBytes field instead of the segments it was written from.
+func TestIhexWriteToMultiSegment(t *testing.T) {
+	const in = ":04000000AABBCCDDEE\n" +
+		":020000040001F9\n" +
+		":02000000EEFF11\n" +
+		":00000001FF\n"
+
+	ih := NewIhex()
+	if err := ih.Parse(bytes.NewBufferString(in)); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(ih.Segments()) != 2 {
+		t.Fatalf("expected 2 segments, got %d: %+v", len(ih.Segments()), ih.Segments())
+	}
+
+	var buf bytes.Buffer
+	if _, err := ih.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+
+	out := NewIhex()
+	if err := out.Parse(&buf); err != nil {
+		t.Fatalf("re-parse: %v", err)
+	}
+	if len(out.Segments()) != 2 {
+		t.Fatalf("expected 2 segments after round trip, got %d: %+v", len(out.Segments()), out.Segments())
+	}
+	if !bytes.Equal(out.Segments()[0].Data, []byte{0xAA, 0xBB, 0xCC, 0xDD}) {
+		t.Fatalf("segment 0 data = %v", out.Segments()[0].Data)
+	}
+	if !bytes.Equal(out.Segments()[1].Data, []byte{0xEE, 0xFF}) {
+		t.Fatalf("segment 1 data = %v", out.Segments()[1].Data)
+	}
+	if out.Segments()[1].Addr != 0x10000 {
+		t.Fatalf("segment 1 addr = 0x%08X, want 0x00010000", out.Segments()[1].Addr)
+	}
+}
+
+func TestIhexParseWithOptionsGapThreshold(t *testing.T) {
+	const in = ":02000000AABB99\n" +
+		":02000400CCDD51\n" +
+		":00000001FF\n"
+
+	ih := NewIhex()
+	err := ih.ParseWithOptions(bytes.NewBufferString(in), ParseOptions{FillByte: 0xFF, GapThreshold: 1})
+	if err != nil {
+		t.Fatalf("ParseWithOptions: %v", err)
+	}
+	if len(ih.Segments()) != 2 {
+		t.Fatalf("expected a small gap threshold to split into 2 segments, got %d: %+v", len(ih.Segments()), ih.Segments())
+	}
+}
@@ -0,0 +1,145 @@
+package srec
+
+import "fmt"
+
+// defaultGapThreshold is how large a hole between two adjacent records
+// can be under GapSplit before a new Segment is started instead of
+// padding across it.
+const defaultGapThreshold = 4096
+
+// Segment is one contiguous run of bytes at a fixed address.
+type Segment struct {
+	Addr uint32
+	Data []byte
+}
+
+// MemoryImage is the view of firmware bytes shared by Srec and Ihex.
+// Bytes() mirrors the single Segment when there's only one; images with
+// wider gaps are left as multiple segments, readable via Segments().
+type MemoryImage struct {
+	startAddress uint32
+	endAddress   uint32
+	dataBytes    []byte
+	segments     []Segment
+	fillByte     byte
+	growOnWrite  bool
+}
+
+func (mi *MemoryImage) Bytes() []byte {
+	return mi.dataBytes
+}
+
+func (mi *MemoryImage) Segments() []Segment {
+	return mi.segments
+}
+
+// SetBytes writes wBytes at wAddr into whichever segment covers that
+// address, growing the last segment with fillByte instead of failing if
+// growOnWrite is set (GapPad parses).
+func (mi *MemoryImage) SetBytes(wAddr uint32, wBytes []byte) error {
+	if len(mi.segments) == 0 {
+		return fmt.Errorf("byte data is empty. call ParseFile() or Parse() first.")
+	}
+
+	for i := range mi.segments {
+		seg := &mi.segments[i]
+		segEnd := seg.Addr + uint32(len(seg.Data))
+		if wAddr < seg.Addr || wAddr >= segEnd {
+			continue
+		}
+		return mi.writeIntoSegment(i, wAddr, wBytes)
+	}
+
+	last := len(mi.segments) - 1
+	lastEnd := mi.segments[last].Addr + uint32(len(mi.segments[last].Data))
+	if mi.growOnWrite && wAddr >= lastEnd {
+		for mi.segments[last].Addr+uint32(len(mi.segments[last].Data)) < wAddr {
+			mi.segments[last].Data = append(mi.segments[last].Data, mi.fillByte)
+		}
+		return mi.writeIntoSegment(last, wAddr, wBytes)
+	}
+
+	return fmt.Errorf("data address 0x%08X is not covered by any segment.", wAddr)
+}
+
+func (mi *MemoryImage) writeIntoSegment(i int, wAddr uint32, wBytes []byte) error {
+	seg := &mi.segments[i]
+	start := int(wAddr - seg.Addr)
+	need := start + len(wBytes)
+
+	if need > len(seg.Data) {
+		if !mi.growOnWrite {
+			return fmt.Errorf("data at 0x%08X overruns the segment at 0x%08X (len %d).", wAddr, seg.Addr, len(seg.Data))
+		}
+		for len(seg.Data) < need {
+			seg.Data = append(seg.Data, mi.fillByte)
+		}
+		if i == len(mi.segments)-1 {
+			mi.endAddress = seg.Addr + uint32(len(seg.Data)) - 1
+			if len(mi.segments) == 1 {
+				mi.dataBytes = seg.Data
+			}
+		}
+	}
+	copy(seg.Data[start:], wBytes)
+	return nil
+}
+
+// addrRun is one already-parsed record's address and bytes, whether it
+// came from an S-record or an Intel HEX file.
+type addrRun struct {
+	addr uint32
+	data []byte
+}
+
+// buildSegments merges ascending records into segments according to
+// policy: GapPad pads every gap into one segment, GapSplit pads gaps up
+// to gapThreshold and starts a new segment across anything wider, and
+// GapError rejects any gap at all.
+func buildSegments(runs []addrRun, fill byte, gapThreshold uint32, policy GapPolicy) ([]Segment, error) {
+	if len(runs) == 0 {
+		return nil, nil
+	}
+
+	segments := []Segment{{Addr: runs[0].addr}}
+	cur := &segments[len(segments)-1]
+	curEnd := runs[0].addr
+
+	for _, run := range runs {
+		// run.addr < curEnd means this record overlaps the current
+		// segment (isAddrAcending only rejects decreasing start
+		// addresses, not overlapping ranges). Overwrite in place instead
+		// of computing run.addr-curEnd, which would underflow.
+		if run.addr < curEnd {
+			overlap := curEnd - run.addr
+			if overlap > uint32(len(run.data)) {
+				overlap = uint32(len(run.data))
+			}
+			segOff := run.addr - cur.Addr
+			copy(cur.Data[segOff:segOff+overlap], run.data[:overlap])
+			if uint32(len(run.data)) > overlap {
+				cur.Data = append(cur.Data, run.data[overlap:]...)
+				curEnd = run.addr + uint32(len(run.data))
+			}
+			continue
+		}
+
+		gap := run.addr - curEnd
+		if len(cur.Data) > 0 && gap > 0 {
+			switch {
+			case policy == GapError:
+				return nil, fmt.Errorf("gap of %d byte(s) before address 0x%08X is not allowed under GapError.", gap, run.addr)
+			case policy == GapSplit && gap > gapThreshold:
+				segments = append(segments, Segment{Addr: run.addr})
+				cur = &segments[len(segments)-1]
+			default:
+				for i := uint32(0); i < gap; i++ {
+					cur.Data = append(cur.Data, fill)
+				}
+			}
+		}
+		cur.Data = append(cur.Data, run.data...)
+		curEnd = run.addr + uint32(len(run.data))
+	}
+	return segments, nil
+}
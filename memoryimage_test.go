@@ -0,0 +1,90 @@
+package srec
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBuildSegmentsGapPad(t *testing.T) {
+	runs := []addrRun{
+		{addr: 0x0000, data: []byte{1, 2, 3}},
+		{addr: 0x0006, data: []byte{4, 5}},
+	}
+	segments, err := buildSegments(runs, 0xFF, defaultGapThreshold, GapPad)
+	if err != nil {
+		t.Fatalf("buildSegments: %v", err)
+	}
+	if len(segments) != 1 {
+		t.Fatalf("expected 1 segment, got %d", len(segments))
+	}
+	want := []byte{1, 2, 3, 0xFF, 0xFF, 0xFF, 4, 5}
+	if !bytes.Equal(segments[0].Data, want) {
+		t.Fatalf("got %v, want %v", segments[0].Data, want)
+	}
+}
+
+func TestBuildSegmentsGapSplit(t *testing.T) {
+	runs := []addrRun{
+		{addr: 0x0000, data: []byte{1, 2, 3}},
+		{addr: 0x10000, data: []byte{4, 5}},
+	}
+	segments, err := buildSegments(runs, 0xFF, defaultGapThreshold, GapSplit)
+	if err != nil {
+		t.Fatalf("buildSegments: %v", err)
+	}
+	if len(segments) != 2 {
+		t.Fatalf("expected 2 segments, got %d", len(segments))
+	}
+	if segments[0].Addr != 0x0000 || segments[1].Addr != 0x10000 {
+		t.Fatalf("unexpected segment addresses: %+v", segments)
+	}
+}
+
+func TestBuildSegmentsGapError(t *testing.T) {
+	runs := []addrRun{
+		{addr: 0x0000, data: []byte{1, 2, 3}},
+		{addr: 0x0006, data: []byte{4, 5}},
+	}
+	if _, err := buildSegments(runs, 0xFF, defaultGapThreshold, GapError); err == nil {
+		t.Fatal("expected an error for a gap under GapError, got nil")
+	}
+}
+
+// TestBuildSegmentsOverlap guards against the uint32 underflow that used
+// to happen when a later record's address was less than the current
+// segment's end: gap := run.addr - curEnd would wrap to ~4 billion.
+func TestBuildSegmentsOverlap(t *testing.T) {
+	runs := []addrRun{
+		{addr: 0x0000, data: []byte{1, 2, 3, 4, 5, 6, 7, 8}},
+		{addr: 0x0003, data: []byte{9, 10, 11}},
+	}
+	segments, err := buildSegments(runs, 0xFF, defaultGapThreshold, GapPad)
+	if err != nil {
+		t.Fatalf("buildSegments: %v", err)
+	}
+	if len(segments) != 1 {
+		t.Fatalf("expected 1 segment, got %d", len(segments))
+	}
+	want := []byte{1, 2, 3, 9, 10, 11, 7, 8}
+	if !bytes.Equal(segments[0].Data, want) {
+		t.Fatalf("got %v, want %v", segments[0].Data, want)
+	}
+}
+
+func TestBuildSegmentsOverlapExtendsPastEnd(t *testing.T) {
+	runs := []addrRun{
+		{addr: 0x0000, data: []byte{1, 2, 3}},
+		{addr: 0x0001, data: []byte{9, 10, 11, 12}},
+	}
+	segments, err := buildSegments(runs, 0xFF, defaultGapThreshold, GapPad)
+	if err != nil {
+		t.Fatalf("buildSegments: %v", err)
+	}
+	if len(segments) != 1 {
+		t.Fatalf("expected 1 segment, got %d", len(segments))
+	}
+	want := []byte{1, 9, 10, 11, 12}
+	if !bytes.Equal(segments[0].Data, want) {
+		t.Fatalf("got %v, want %v", segments[0].Data, want)
+	}
+}
@@ -0,0 +1,50 @@
+package srec
+
+// GapPolicy controls how ParseFileWithOptions handles a hole between two
+// data records.
+type GapPolicy int
+
+const (
+	// GapPad fills every gap with FillByte, the historical ParseFile behavior.
+	GapPad GapPolicy = iota
+	// GapSplit pads gaps up to GapThreshold (defaultGapThreshold when
+	// unset) and starts a new segment across anything wider.
+	GapSplit
+	// GapError rejects any image that has a hole at all.
+	GapError
+)
+
+// ParseOptions configures ParseFileWithOptions.
+type ParseOptions struct {
+	// FillByte pads gaps and, under GapPad, grows the image when
+	// SetBytes writes past the current end address. Zero value is a
+	// literal 0x00 fill; use DefaultParseOptions for 0xFF.
+	FillByte byte
+	// GapPolicy selects how holes between records are handled. Zero
+	// value is GapPad, matching ParseFile's historical behavior.
+	GapPolicy GapPolicy
+	// RequireAscendingAddr rejects files whose data records are not in
+	// ascending address order when true, the historical ParseFile
+	// behavior.
+	RequireAscendingAddr bool
+	// GapThreshold is how large a hole can be under GapSplit before a
+	// new Segment is started instead of padding across it. Zero means
+	// defaultGapThreshold. Ignored by GapPad and GapError.
+	GapThreshold uint32
+}
+
+// DefaultParseOptions is what ParseFile uses: 0xFF fill, gaps padded into
+// one flat buffer, and ascending addresses required.
+var DefaultParseOptions = ParseOptions{
+	FillByte:             0xFF,
+	GapPolicy:            GapPad,
+	RequireAscendingAddr: true,
+}
+
+// gapThreshold returns opts.GapThreshold, or defaultGapThreshold if unset.
+func (opts ParseOptions) gapThreshold() uint32 {
+	if opts.GapThreshold == 0 {
+		return defaultGapThreshold
+	}
+	return opts.GapThreshold
+}
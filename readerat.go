@@ -0,0 +1,65 @@
+package srec
+
+import (
+	"fmt"
+	"io"
+)
+
+// ReaderAt exposes the image as an io.ReaderAt spanning
+// [startAddress, endAddress] without materializing the whole range:
+// reads are resolved by walking the segment list and synthesizing
+// fillByte for any address a segment doesn't cover.
+func (mi *MemoryImage) ReaderAt() io.ReaderAt {
+	return &memoryImageReaderAt{
+		segments: mi.segments,
+		fill:     mi.fillByte,
+		base:     mi.startAddress,
+		size:     int64(mi.endAddress) - int64(mi.startAddress) + 1,
+	}
+}
+
+type memoryImageReaderAt struct {
+	segments []Segment
+	fill     byte
+	base     uint32
+	size     int64
+}
+
+func (r *memoryImageReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("srec: ReadAt: negative offset %d.", off)
+	}
+	if off >= r.size {
+		return 0, io.EOF
+	}
+
+	end := off + int64(len(p))
+	if end > r.size {
+		end = r.size
+	}
+	for i := range p[:end-off] {
+		p[i] = r.fill
+	}
+
+	for _, seg := range r.segments {
+		segStart := int64(seg.Addr) - int64(r.base)
+		segEnd := segStart + int64(len(seg.Data))
+
+		lo, hi := off, end
+		if segStart > lo {
+			lo = segStart
+		}
+		if segEnd < hi {
+			hi = segEnd
+		}
+		if lo < hi {
+			copy(p[lo-off:hi-off], seg.Data[lo-segStart:hi-segStart])
+		}
+	}
+
+	n := int(end - off)
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
@@ -0,0 +1,62 @@
+package srec
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestReaderAtSpansSegmentBoundary reads across a gap between two
+// segments in one call and checks the gap bytes come back as fillByte
+// while each segment's own bytes are read verbatim.
+func TestReaderAtSpansSegmentBoundary(t *testing.T) {
+	mi := &MemoryImage{
+		startAddress: 0,
+		endAddress:   9,
+		fillByte:     0xFF,
+		segments: []Segment{
+			{Addr: 0, Data: []byte{1, 2, 3}},
+			{Addr: 7, Data: []byte{4, 5, 6}},
+		},
+	}
+
+	got := make([]byte, 10)
+	n, err := mi.ReaderAt().ReadAt(got, 0)
+	if err != nil {
+		t.Fatalf("ReadAt: %v", err)
+	}
+	if n != 10 {
+		t.Fatalf("read %d bytes, want 10", n)
+	}
+	want := []byte{1, 2, 3, 0xFF, 0xFF, 0xFF, 0xFF, 4, 5, 6}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+// TestReaderAtPartialReadAtBoundary reads a window that starts inside
+// the gap and ends inside the second segment, to exercise the
+// segStart/segEnd clamping at a non-zero offset.
+func TestReaderAtPartialReadAtBoundary(t *testing.T) {
+	mi := &MemoryImage{
+		startAddress: 0,
+		endAddress:   9,
+		fillByte:     0xFF,
+		segments: []Segment{
+			{Addr: 0, Data: []byte{1, 2, 3}},
+			{Addr: 7, Data: []byte{4, 5, 6}},
+		},
+	}
+
+	got := make([]byte, 4)
+	n, err := mi.ReaderAt().ReadAt(got, 5)
+	if err != nil {
+		t.Fatalf("ReadAt: %v", err)
+	}
+	if n != 4 {
+		t.Fatalf("read %d bytes, want 4", n)
+	}
+	want := []byte{0xFF, 0xFF, 4, 5}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
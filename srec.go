@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -18,13 +19,13 @@ type Srec struct {
 	headerRecord *headerRecord
 	dataRecords  []*dataRecord
 	footerRecord *footerRecord
-	startAddress uint32
-	endAddress   uint32
-	dataBytes    []byte
+	lenient      bool
+	MemoryImage
 }
 
 type headerRecord struct {
 	length   uint32
+	address  uint32
 	data     []byte
 	checksum byte
 }
@@ -59,10 +60,48 @@ func newFooterRecord() *footerRecord {
 	return &footerRecord{}
 }
 
+func (rec *footerRecord) getFooterRecordFields(srectype string, sl []string) error {
+	var err error
+
+	rec.srectype = srectype
+	addrtype := opposingDataType(srectype)
+	rec.entryAddr, err = getAddress(addrtype, sl)
+	if err != nil {
+		return err
+	}
+	rec.checksum, err = getChecksum(addrtype, sl)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Parse is an alias for ParseFile so Srec satisfies HexFile.
+func (srs *Srec) Parse(fileReader io.Reader) error {
+	return srs.ParseFile(fileReader)
+}
+
+// SetStrict toggles checksum verification during ParseFile. Verification
+// is on by default; call SetStrict(false) to recover dumps whose
+// checksums are known to be damaged.
+func (srs *Srec) SetStrict(strict bool) {
+	srs.lenient = !strict
+}
+
+// ParseFile parses an S-record file with DefaultParseOptions.
 func (srs *Srec) ParseFile(fileReader io.Reader) error {
+	return srs.ParseFileWithOptions(fileReader, DefaultParseOptions)
+}
+
+// ParseFileWithOptions parses an S-record file the way ParseFile does,
+// but with control over the fill byte, gap handling and address-order
+// check via opts.
+func (srs *Srec) ParseFileWithOptions(fileReader io.Reader, opts ParseOptions) error {
 	scanner := bufio.NewScanner(fileReader)
 
+	lineNo := 0
 	for scanner.Scan() {
+		lineNo++
 		splitedLine := strings.Split(scanner.Text(), "")
 
 		srectype := strings.Join(splitedLine[:2], "")
@@ -73,6 +112,9 @@ func (srs *Srec) ParseFile(fileReader io.Reader) error {
 			if err != nil {
 				return err
 			}
+			if err := srs.checkChecksum(lineNo, rec.length, rec.address, rec.data, rec.checksum); err != nil {
+				return err
+			}
 			srs.headerRecord = rec
 		case (srectype == "S1") || (srectype == "S2") || (srectype == "S3"):
 			rec := newBianryRecord()
@@ -80,8 +122,24 @@ func (srs *Srec) ParseFile(fileReader io.Reader) error {
 			if err != nil {
 				return err
 			}
+			if err := srs.checkChecksum(lineNo, rec.length, rec.address, rec.data, rec.checksum); err != nil {
+				return err
+			}
 			srs.dataRecords = append(srs.dataRecords, rec)
 		case (srectype == "S7") || (srectype == "S8") || (srectype == "S9"):
+			rec := newFooterRecord()
+			err := rec.getFooterRecordFields(srectype, splitedLine)
+			if err != nil {
+				return err
+			}
+			length, err := getLengh(splitedLine)
+			if err != nil {
+				return err
+			}
+			if err := srs.checkChecksum(lineNo, length, rec.entryAddr, nil, rec.checksum); err != nil {
+				return err
+			}
+			srs.footerRecord = rec
 		default:
 			// pass S4~6
 		}
@@ -91,20 +149,15 @@ func (srs *Srec) ParseFile(fileReader io.Reader) error {
 	if err != nil {
 		return err
 	}
-	err = srs.isAddrAcending()
-	if err != nil {
-		return err
+	if opts.RequireAscendingAddr {
+		if err := srs.isAddrAcending(); err != nil {
+			return err
+		}
 	}
 
-	srs.startAddress = getStartAddr(srs)
-	srs.endAddress = getEndAddr(srs)
-	LastRecordDatalen := getLastRecordDataLen(srs)
+	srs.startAddress, srs.endAddress = srecAddrRange(srs.dataRecords)
 
-	err = srs.makePaddedBytes(srs.startAddress, srs.endAddress, LastRecordDatalen)
-	if err != nil {
-		return err
-	}
-	return nil
+	return srs.makePaddedBytes(opts)
 }
 
 func (rec *headerRecord) getHeaderRecordFields(sl []string) error {
@@ -115,6 +168,10 @@ func (rec *headerRecord) getHeaderRecordFields(sl []string) error {
 	if err != nil {
 		return err
 	}
+	rec.address, err = getAddress(srectype, sl)
+	if err != nil {
+		return err
+	}
 	rec.data, err = getData(srectype, sl)
 	if err != nil {
 		return err
@@ -234,6 +291,17 @@ func (sr *Srec) isDataRecordExists() error {
 	return nil
 }
 
+func (sr *Srec) checkChecksum(lineNo int, length uint32, addr uint32, data []byte, checksum byte) error {
+	if sr.lenient {
+		return nil
+	}
+	expected := ComputeChecksum(length, addr, data)
+	if expected != checksum {
+		return fmt.Errorf("srec line %d: checksum mismatch: expected 0x%02X, got 0x%02X.", lineNo, expected, checksum)
+	}
+	return nil
+}
+
 func (sr *Srec) isAddrAcending() error {
 	var prevAddr uint32
 	for i, brec := range sr.dataRecords {
@@ -248,51 +316,42 @@ func (sr *Srec) isAddrAcending() error {
 	return nil
 }
 
-func getStartAddr(sr *Srec) uint32 {
-	return sr.dataRecords[0].address
-}
-
-func getEndAddr(sr *Srec) uint32 {
-	return sr.dataRecords[len(sr.dataRecords)-1].address
-}
-
-func getLastRecordDataLen(sr *Srec) uint32 {
-	len := len(sr.dataRecords[len(sr.dataRecords)-1].data)
-	return uint32(len)
-}
-
-func (sr *Srec) makePaddedBytes(startAddr uint32, endAddr uint32, lastRecordDataLen uint32) error {
-	size := (endAddr - startAddr) + lastRecordDataLen
-	for i := 0; i < int(size); i++ {
-		sr.dataBytes = append(sr.dataBytes, 0xFF)
+// srecAddrRange returns the lowest and highest byte address covered by
+// records, regardless of the order they appear in.
+func srecAddrRange(records []*dataRecord) (start uint32, end uint32) {
+	start = records[0].address
+	end = records[0].address + uint32(len(records[0].data)) - 1
+	for _, rec := range records[1:] {
+		if rec.address < start {
+			start = rec.address
+		}
+		if recEnd := rec.address + uint32(len(rec.data)) - 1; recEnd > end {
+			end = recEnd
+		}
 	}
+	return start, end
+}
 
-	ofst := int(startAddr)
+func (sr *Srec) makePaddedBytes(opts ParseOptions) error {
+	runs := make([]addrRun, 0, len(sr.dataRecords))
 	for _, brcs := range sr.dataRecords {
-		for i := 0; i < len(brcs.data); i++ {
-			if (brcs.address < sr.startAddress) || (brcs.address > sr.endAddress) {
-				return fmt.Errorf("data address 0x%08X is out of srec range.", brcs.address)
-			}
-			sr.dataBytes[(int(brcs.address)-ofst)+i] = brcs.data[i]
+		if (brcs.address < sr.startAddress) || (brcs.address > sr.endAddress) {
+			return fmt.Errorf("data address 0x%08X is out of srec range.", brcs.address)
 		}
+		runs = append(runs, addrRun{addr: brcs.address, data: brcs.data})
 	}
-	return nil
-}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].addr < runs[j].addr })
 
-func (sr *Srec) Bytes() []byte {
-	return sr.dataBytes
-}
+	sr.fillByte = opts.FillByte
+	sr.growOnWrite = opts.GapPolicy == GapPad
 
-func (sr *Srec) SetBytes(wAddr uint32, wBytes []byte) error {
-	if len(sr.dataRecords) == 0 {
-		return fmt.Errorf("byte data is empty. call PaeseFile() or maybe srec file has no S1~3 records.")
-	}
-	if (wAddr < sr.startAddress) || (wAddr > sr.endAddress) {
-		return fmt.Errorf("data address 0x%08X is out of srec range.", wAddr)
+	segments, err := buildSegments(runs, opts.FillByte, opts.gapThreshold(), opts.GapPolicy)
+	if err != nil {
+		return err
 	}
-	start := int(wAddr) - int(sr.startAddress)
-	for i := 0; i < len(wBytes); i++ {
-		sr.dataBytes[start+i] = wBytes[i]
+	sr.segments = segments
+	if len(sr.segments) == 1 {
+		sr.dataBytes = sr.segments[0].Data
 	}
 	return nil
 }
@@ -0,0 +1,37 @@
+package srec
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestParseFileChecksumStrictByDefault guards chunk0-4's strict/lenient
+// toggle: ParseFile must verify checksums by default, accepting a file
+// whose checksums are correct...
+func TestParseFileChecksumStrictByDefault(t *testing.T) {
+	const good = "S00600004844521B\nS1060000AABBCCC8\nS9030000FC\n"
+	s := NewSrec()
+	if err := s.ParseFile(bytes.NewBufferString(good)); err != nil {
+		t.Fatalf("ParseFile with a good checksum: %v", err)
+	}
+}
+
+// ...and rejecting one with a corrupted checksum.
+func TestParseFileChecksumRejectsCorrupted(t *testing.T) {
+	const bad = "S00600004844521B\nS1060000AABBCC00\nS9030000FC\n"
+	s := NewSrec()
+	if err := s.ParseFile(bytes.NewBufferString(bad)); err == nil {
+		t.Fatal("expected a checksum mismatch error, got nil")
+	}
+}
+
+// TestParseFileChecksumLenient confirms SetStrict(false) recovers a file
+// whose checksums are known to be damaged.
+func TestParseFileChecksumLenient(t *testing.T) {
+	const bad = "S00600004844521B\nS1060000AABBCC00\nS9030000FC\n"
+	s := NewSrec()
+	s.SetStrict(false)
+	if err := s.ParseFile(bytes.NewBufferString(bad)); err != nil {
+		t.Fatalf("lenient ParseFile: %v", err)
+	}
+}
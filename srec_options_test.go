@@ -0,0 +1,89 @@
+package srec
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestParseFileWithOptionsFillByteAndGapPad checks that a custom
+// FillByte is used to pad the gap between two records under the
+// default GapPad policy.
+func TestParseFileWithOptionsFillByteAndGapPad(t *testing.T) {
+	const in = "S00600004844521B\n" +
+		"S1060000AABBCCC8\n" +
+		"S1060006DDEEFF29\n" +
+		"S9030000FC\n"
+
+	s := NewSrec()
+	opts := ParseOptions{FillByte: 0x00, GapPolicy: GapPad, RequireAscendingAddr: true}
+	if err := s.ParseFileWithOptions(bytes.NewBufferString(in), opts); err != nil {
+		t.Fatalf("ParseFileWithOptions: %v", err)
+	}
+	want := []byte{0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00, 0xDD, 0xEE, 0xFF}
+	if !bytes.Equal(s.Bytes(), want) {
+		t.Fatalf("got %v, want %v", s.Bytes(), want)
+	}
+}
+
+// TestParseFileWithOptionsGapSplit checks that a gap wider than
+// GapThreshold starts a new segment instead of being padded.
+func TestParseFileWithOptionsGapSplit(t *testing.T) {
+	const in = "S00600004844521B\n" +
+		"S1060000AABBCCC8\n" +
+		"S1060006DDEEFF29\n" +
+		"S9030000FC\n"
+
+	s := NewSrec()
+	opts := ParseOptions{FillByte: 0xFF, GapPolicy: GapSplit, RequireAscendingAddr: true, GapThreshold: 1}
+	if err := s.ParseFileWithOptions(bytes.NewBufferString(in), opts); err != nil {
+		t.Fatalf("ParseFileWithOptions: %v", err)
+	}
+	if len(s.Segments()) != 2 {
+		t.Fatalf("expected 2 segments, got %d: %+v", len(s.Segments()), s.Segments())
+	}
+}
+
+// TestParseFileWithOptionsRequireAscendingAddr checks that out-of-order
+// records are rejected when RequireAscendingAddr is true and accepted
+// when it's false.
+func TestParseFileWithOptionsRequireAscendingAddr(t *testing.T) {
+	const in = "S00600004844521B\n" +
+		"S1060000AABBCCC8\n" +
+		"S1060006DDEEFF29\n" +
+		"S106000311223390\n" +
+		"S9030000FC\n"
+
+	s := NewSrec()
+	strict := ParseOptions{FillByte: 0xFF, GapPolicy: GapPad, RequireAscendingAddr: true}
+	if err := s.ParseFileWithOptions(bytes.NewBufferString(in), strict); err == nil {
+		t.Fatal("expected an out-of-order address error, got nil")
+	}
+
+	s = NewSrec()
+	lax := ParseOptions{FillByte: 0xFF, GapPolicy: GapPad, RequireAscendingAddr: false}
+	if err := s.ParseFileWithOptions(bytes.NewBufferString(in), lax); err != nil {
+		t.Fatalf("ParseFileWithOptions with RequireAscendingAddr false: %v", err)
+	}
+}
+
+// TestSetBytesGrowsPastEndAddressUnderGapPad checks that a GapPad parse
+// lets SetBytes grow the image past its current end address instead of
+// failing, since growOnWrite is only set true for GapPad.
+func TestSetBytesGrowsPastEndAddressUnderGapPad(t *testing.T) {
+	const in = "S00600004844521B\n" +
+		"S1060000AABBCCC8\n" +
+		"S9030000FC\n"
+
+	s := NewSrec()
+	if err := s.ParseFileWithOptions(bytes.NewBufferString(in), DefaultParseOptions); err != nil {
+		t.Fatalf("ParseFileWithOptions: %v", err)
+	}
+
+	if err := s.SetBytes(3, []byte{0x11, 0x22}); err != nil {
+		t.Fatalf("SetBytes past endAddress under GapPad: %v", err)
+	}
+	want := []byte{0xAA, 0xBB, 0xCC, 0x11, 0x22}
+	if !bytes.Equal(s.Bytes(), want) {
+		t.Fatalf("got %v, want %v", s.Bytes(), want)
+	}
+}
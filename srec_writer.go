@@ -0,0 +1,173 @@
+package srec
+
+import (
+	"fmt"
+	"io"
+)
+
+// defaultMaxDataLen is the number of data bytes NewFromBytes packs into
+// each record when Options.MaxDataLen is left at zero.
+const defaultMaxDataLen = 32
+
+// Options configures how NewFromBytes builds a new Srec.
+type Options struct {
+	// MaxDataLen is the maximum number of data bytes per S1/S2/S3 record.
+	// Zero means defaultMaxDataLen.
+	MaxDataLen int
+}
+
+// NewFromBytes builds a new Srec covering the range
+// [startAddr, startAddr+len(data)) from a flat byte slice. The record
+// type (S1/S2/S3) is chosen from the address width the data needs, and
+// data is split into records of at most opts.MaxDataLen bytes.
+func NewFromBytes(startAddr uint32, data []byte, opts Options) *Srec {
+	maxDataLen := opts.MaxDataLen
+	if maxDataLen <= 0 {
+		maxDataLen = defaultMaxDataLen
+	}
+
+	endAddr := startAddr + uint32(len(data))
+	lastAddr := endAddr
+	if len(data) > 0 {
+		lastAddr--
+	}
+	srectype := srecTypeForAddr(lastAddr)
+	addrLen, _ := getAddrLenAsStr(srectype)
+	addrLen /= 2
+
+	sr := &Srec{
+		headerRecord: newHeaderRecord(),
+		footerRecord: &footerRecord{srectype: footerTypeFor(srectype), entryAddr: startAddr},
+	}
+	// S0 always carries a 2-byte address field, whatever the data type.
+	sr.headerRecord.length = 3
+	sr.headerRecord.checksum = ComputeChecksum(sr.headerRecord.length, 0, nil)
+	sr.footerRecord.checksum = ComputeChecksum(uint32(addrLen+1), startAddr, nil)
+
+	for off := 0; off < len(data); off += maxDataLen {
+		end := off + maxDataLen
+		if end > len(data) {
+			end = len(data)
+		}
+		chunk := append([]byte{}, data[off:end]...)
+		addr := startAddr + uint32(off)
+		rec := &dataRecord{
+			srectype: srectype,
+			address:  addr,
+			data:     chunk,
+			length:   uint32(addrLen + len(chunk) + 1),
+		}
+		rec.checksum = ComputeChecksum(rec.length, addr, chunk)
+		sr.dataRecords = append(sr.dataRecords, rec)
+	}
+
+	buf := append([]byte{}, data...)
+	sr.MemoryImage = MemoryImage{
+		startAddress: startAddr,
+		endAddress:   endAddr - 1,
+		dataBytes:    buf,
+		segments:     []Segment{{Addr: startAddr, Data: buf}},
+		fillByte:     0xFF,
+	}
+	return sr
+}
+
+// srecTypeForAddr picks the narrowest S-record data type whose address
+// field can hold addr.
+func srecTypeForAddr(addr uint32) string {
+	switch {
+	case addr <= 0xFFFF:
+		return "S1"
+	case addr <= 0xFFFFFF:
+		return "S2"
+	default:
+		return "S3"
+	}
+}
+
+// footerTypeFor returns the termination record matching a data record
+// type: S9/S1, S8/S2, S7/S3.
+func footerTypeFor(srectype string) string {
+	switch srectype {
+	case "S1":
+		return "S9"
+	case "S2":
+		return "S8"
+	default:
+		return "S7"
+	}
+}
+
+// WriteTo serializes the Srec back out in ASCII S-record form: the header,
+// the data records and the footer, each with its stored checksum. The data
+// records written are the ones parsed or built by NewFromBytes; bytes
+// changed through SetBytes are not written out.
+func (sr *Srec) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+
+	if sr.headerRecord != nil {
+		n, err := writeSrecLine(w, "S0", 2, 0, sr.headerRecord.data, sr.headerRecord.checksum)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+
+	for _, rec := range sr.dataRecords {
+		addrLen, err := getAddrLenAsStr(rec.srectype)
+		if err != nil {
+			return written, err
+		}
+		n, err := writeSrecLine(w, rec.srectype, addrLen/2, rec.address, rec.data, rec.checksum)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+
+	if sr.footerRecord != nil {
+		addrLen, err := getAddrLenAsStr(opposingDataType(sr.footerRecord.srectype))
+		if err != nil {
+			return written, err
+		}
+		n, err := writeSrecLine(w, sr.footerRecord.srectype, addrLen/2, sr.footerRecord.entryAddr, nil, sr.footerRecord.checksum)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+// opposingDataType maps a footer type back to the data type it closes,
+// so its address field width can be looked up via getAddrLenAsStr.
+func opposingDataType(footerType string) string {
+	switch footerType {
+	case "S9":
+		return "S1"
+	case "S8":
+		return "S2"
+	default:
+		return "S3"
+	}
+}
+
+func writeSrecLine(w io.Writer, srectype string, addrLen int, addr uint32, data []byte, checksum byte) (int64, error) {
+	length := addrLen + len(data) + 1
+	n, err := fmt.Fprintf(w, "%s%02X%0*X", srectype, length, addrLen*2, addr)
+	if err != nil {
+		return int64(n), err
+	}
+	written := int64(n)
+	for _, b := range data {
+		n, err = fmt.Fprintf(w, "%02X", b)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	n, err = fmt.Fprintf(w, "%02X\n", checksum)
+	written += int64(n)
+	return written, err
+}
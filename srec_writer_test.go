@@ -0,0 +1,37 @@
+package srec
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNewFromBytesWriteToRoundTrip(t *testing.T) {
+	data := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	sr := NewFromBytes(0x1000, data, Options{MaxDataLen: 4})
+
+	var buf bytes.Buffer
+	if _, err := sr.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+
+	out := NewSrec()
+	if err := out.ParseFile(&buf); err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), data) {
+		t.Fatalf("got %v, want %v", out.Bytes(), data)
+	}
+}
+
+// TestNewFromBytesSetBytes guards against NewFromBytes leaving segments
+// unset, which used to make SetBytes fail on every Srec it built.
+func TestNewFromBytesSetBytes(t *testing.T) {
+	sr := NewFromBytes(0, []byte{1, 2, 3, 4}, Options{})
+	if err := sr.SetBytes(1, []byte{0xAA}); err != nil {
+		t.Fatalf("SetBytes: %v", err)
+	}
+	want := []byte{1, 0xAA, 3, 4}
+	if !bytes.Equal(sr.Bytes(), want) {
+		t.Fatalf("got %v, want %v", sr.Bytes(), want)
+	}
+}